@@ -0,0 +1,100 @@
+package client
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"time"
+
+	manifold "github.com/manifoldco/go-manifold"
+	"github.com/manifoldco/kubernetes-credentials/primitives"
+)
+
+// credCacheEntry is a cached, already-resolved set of credentials for a
+// single resource, along with when it stops being valid.
+type credCacheEntry struct {
+	creds     []*primitives.CredentialValue
+	expiresAt time.Time
+}
+
+// credCacheKey identifies a resource within the credential cache by project,
+// label and the fingerprint of the CredentialSpecs that were requested for
+// it. The fingerprint is required: two callers asking for different aliases,
+// defaults or templates on the same resource label must not be served each
+// other's resolved values.
+func credCacheKey(pID *manifold.ID, label, specFingerprint string) string {
+	project := ""
+	if pID != nil {
+		project = pID.String()
+	}
+
+	return project + "/" + label + "/" + specFingerprint
+}
+
+// credentialSpecFingerprint returns a stable digest of the CredentialSpecs
+// requested for label in res, so the cache can tell apart two requests for
+// the same resource that differ in aliasing, defaulting or templating.
+func credentialSpecFingerprint(res []*primitives.Resource, label string) string {
+	for _, r := range res {
+		if r.Spec.Label != label {
+			continue
+		}
+
+		specs := make([]primitives.CredentialSpec, len(r.Spec.Credentials))
+		copy(specs, r.Spec.Credentials)
+		sort.Slice(specs, func(i, j int) bool {
+			return specs[i].Key < specs[j].Key
+		})
+
+		h := sha256.New()
+		for _, s := range specs {
+			h.Write([]byte(s.Key))
+			h.Write([]byte{0})
+			h.Write([]byte(s.Alias))
+			h.Write([]byte{0})
+			h.Write([]byte(s.Default))
+			h.Write([]byte{0})
+			h.Write([]byte(s.Template))
+			h.Write([]byte{0})
+		}
+
+		return hex.EncodeToString(h.Sum(nil))
+	}
+
+	return ""
+}
+
+// getCachedCredentials returns a resource's cached credentials if the cache
+// is enabled and the entry hasn't expired.
+func (c *Client) getCachedCredentials(pID *manifold.ID, label, specFingerprint string) ([]*primitives.CredentialValue, bool) {
+	if c.credCacheTTL <= 0 {
+		return nil, false
+	}
+
+	c.RLock()
+	defer c.RUnlock()
+
+	entry, ok := c.credCache[credCacheKey(pID, label, specFingerprint)]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, false
+	}
+
+	return entry.creds, true
+}
+
+// setCachedCredentials stores a resource's resolved credentials, if the
+// cache is enabled, so they can be served to the next caller requesting the
+// same specs until they expire.
+func (c *Client) setCachedCredentials(pID *manifold.ID, label, specFingerprint string, creds []*primitives.CredentialValue) {
+	if c.credCacheTTL <= 0 {
+		return
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	c.credCache[credCacheKey(pID, label, specFingerprint)] = credCacheEntry{
+		creds:     creds,
+		expiresAt: time.Now().Add(c.credCacheTTL),
+	}
+}
@@ -0,0 +1,113 @@
+package client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/manifoldco/kubernetes-credentials/primitives"
+)
+
+func newTestCacheClient(ttl time.Duration) *Client {
+	return &Client{
+		credCacheTTL: ttl,
+		credCache:    map[string]credCacheEntry{},
+	}
+}
+
+func TestCredentialCacheHitAndMiss(t *testing.T) {
+	c := newTestCacheClient(time.Minute)
+
+	fingerprint := credentialSpecFingerprint(nil, "db")
+	if _, ok := c.getCachedCredentials(nil, "db", fingerprint); ok {
+		t.Fatal("expected a miss before anything was cached")
+	}
+
+	creds := []*primitives.CredentialValue{{
+		CredentialSpec: primitives.CredentialSpec{Key: "HOST"},
+		Value:          "db.internal",
+	}}
+	c.setCachedCredentials(nil, "db", fingerprint, creds)
+
+	got, ok := c.getCachedCredentials(nil, "db", fingerprint)
+	if !ok {
+		t.Fatal("expected a hit after caching")
+	}
+	if len(got) != 1 || got[0].Value != "db.internal" {
+		t.Fatalf("unexpected cached value: %+v", got)
+	}
+}
+
+func TestCredentialCacheDisabledWithZeroTTL(t *testing.T) {
+	c := newTestCacheClient(0)
+
+	fingerprint := credentialSpecFingerprint(nil, "db")
+	c.setCachedCredentials(nil, "db", fingerprint, []*primitives.CredentialValue{{
+		CredentialSpec: primitives.CredentialSpec{Key: "HOST"},
+		Value:          "db.internal",
+	}})
+
+	if _, ok := c.getCachedCredentials(nil, "db", fingerprint); ok {
+		t.Fatal("expected caching to be a no-op when the TTL is zero")
+	}
+}
+
+func TestCredentialCacheExpiry(t *testing.T) {
+	c := newTestCacheClient(time.Nanosecond)
+
+	fingerprint := credentialSpecFingerprint(nil, "db")
+	c.setCachedCredentials(nil, "db", fingerprint, []*primitives.CredentialValue{{
+		CredentialSpec: primitives.CredentialSpec{Key: "HOST"},
+		Value:          "db.internal",
+	}})
+
+	time.Sleep(time.Millisecond)
+
+	if _, ok := c.getCachedCredentials(nil, "db", fingerprint); ok {
+		t.Fatal("expected the entry to have expired")
+	}
+}
+
+func TestCredentialSpecFingerprintDiffersByAliasAndTemplate(t *testing.T) {
+	base := []*primitives.Resource{{
+		Spec: primitives.ResourceSpec{
+			Label: "db",
+			Credentials: []primitives.CredentialSpec{
+				{Key: "HOST"},
+			},
+		},
+	}}
+	aliased := []*primitives.Resource{{
+		Spec: primitives.ResourceSpec{
+			Label: "db",
+			Credentials: []primitives.CredentialSpec{
+				{Key: "HOST", Alias: "DB_HOST"},
+			},
+		},
+	}}
+
+	templated := []*primitives.Resource{{
+		Spec: primitives.ResourceSpec{
+			Label: "db",
+			Credentials: []primitives.CredentialSpec{
+				{Key: "HOST", Template: "{{ .HOSTNAME }}"},
+			},
+		},
+	}}
+
+	baseFP := credentialSpecFingerprint(base, "db")
+	aliasedFP := credentialSpecFingerprint(aliased, "db")
+	templatedFP := credentialSpecFingerprint(templated, "db")
+
+	if baseFP == aliasedFP {
+		t.Fatal("expected fingerprints to differ when the requested alias differs")
+	}
+	if baseFP == templatedFP {
+		t.Fatal("expected fingerprints to differ when the requested template differs")
+	}
+
+	// Same shape, requested again, should produce the same fingerprint so
+	// cache hits still happen for identical requests.
+	if credentialSpecFingerprint(base, "db") != baseFP {
+		t.Fatal("expected the fingerprint to be stable across calls")
+	}
+}
@@ -0,0 +1,53 @@
+package client
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+
+	"github.com/manifoldco/kubernetes-credentials/primitives"
+)
+
+// GetResourceCredentialChecksums resolves the credentials for each of the
+// given resources, the same way GetResourcesCredentialValues does (including
+// default-filled values), and returns a stable SHA256 checksum per resource
+// label. Callers use this to stamp a checksum annotation, e.g.
+// `credentials.manifold.co/checksum`, onto the Secret they write and the
+// Deployments that reference it, so that a change to an upstream credential
+// causes those Deployments to roll.
+func (c *Client) GetResourceCredentialChecksums(ctx context.Context, project *string, res []*primitives.Resource) (map[string]string, error) {
+	resourceCredentials, err := c.GetResourcesCredentialValues(ctx, project, res)
+	if err != nil {
+		return nil, err
+	}
+
+	checksums := make(map[string]string, len(resourceCredentials))
+	for label, creds := range resourceCredentials {
+		checksums[label] = checksumCredentialValues(creds)
+	}
+
+	return checksums, nil
+}
+
+// checksumCredentialValues computes a canonical SHA256 checksum over the
+// (key, value) pairs of creds. The pairs are sorted by key first so the
+// digest is deterministic regardless of the order credentials were returned
+// in or Go's randomized map iteration order.
+func checksumCredentialValues(creds []*primitives.CredentialValue) string {
+	sorted := make([]*primitives.CredentialValue, len(creds))
+	copy(sorted, creds)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Key < sorted[j].Key
+	})
+
+	h := sha256.New()
+	for _, cv := range sorted {
+		h.Write([]byte(cv.Key))
+		h.Write([]byte{0})
+		h.Write([]byte(cv.Value))
+		h.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
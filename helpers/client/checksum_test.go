@@ -0,0 +1,35 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/manifoldco/kubernetes-credentials/primitives"
+)
+
+func TestChecksumCredentialValuesDeterministic(t *testing.T) {
+	a := []*primitives.CredentialValue{
+		{CredentialSpec: primitives.CredentialSpec{Key: "HOST"}, Value: "db"},
+		{CredentialSpec: primitives.CredentialSpec{Key: "PORT"}, Value: "5432"},
+	}
+	b := []*primitives.CredentialValue{
+		{CredentialSpec: primitives.CredentialSpec{Key: "PORT"}, Value: "5432"},
+		{CredentialSpec: primitives.CredentialSpec{Key: "HOST"}, Value: "db"},
+	}
+
+	if checksumCredentialValues(a) != checksumCredentialValues(b) {
+		t.Fatal("expected the checksum to be independent of input order")
+	}
+}
+
+func TestChecksumCredentialValuesChangesWithValue(t *testing.T) {
+	a := []*primitives.CredentialValue{
+		{CredentialSpec: primitives.CredentialSpec{Key: "HOST"}, Value: "db"},
+	}
+	b := []*primitives.CredentialValue{
+		{CredentialSpec: primitives.CredentialSpec{Key: "HOST"}, Value: "other-db"},
+	}
+
+	if checksumCredentialValues(a) == checksumCredentialValues(b) {
+		t.Fatal("expected the checksum to change when a value changes")
+	}
+}
@@ -4,11 +4,18 @@ import (
 	"context"
 	"errors"
 	"sync"
+	"time"
 
 	manifold "github.com/manifoldco/go-manifold"
 	"github.com/manifoldco/kubernetes-credentials/primitives"
+	"golang.org/x/sync/errgroup"
 )
 
+// defaultConcurrency bounds how many per-resource credential fetches
+// GetResourcesCredentialValues issues at once, unless overridden with
+// WithConcurrency.
+const defaultConcurrency = 8
+
 var (
 	ErrLabelRequired          = errors.New("A label is required to perform this query.")
 	ErrResourceInvalid        = errors.New("The resource is invalid")
@@ -25,19 +32,51 @@ var (
 // Client is a wrapper around the manifold client.
 type Client struct {
 	sync.RWMutex
-	cl         *manifold.Client
-	team       *string
-	teamID     *manifold.ID
-	projectIDs map[string]*manifold.ID
+	cl           *manifold.Client
+	team         *string
+	teamID       *manifold.ID
+	projectIDs   map[string]*manifold.ID
+	concurrency  int
+	credCacheTTL time.Duration
+	credCache    map[string]credCacheEntry
+}
+
+// ClientOption configures optional behavior on a Client returned by New.
+type ClientOption func(*Client)
+
+// WithConcurrency bounds how many per-resource credential fetches
+// GetResourcesCredentialValues issues at once. It defaults to 8.
+func WithConcurrency(n int) ClientOption {
+	return func(c *Client) {
+		c.concurrency = n
+	}
+}
+
+// WithCredentialCacheTTL enables an in-memory cache of resolved credentials,
+// keyed by project, resource label and the requested CredentialSpecs, so
+// repeated calls within ttl of each other (e.g. from a Reconciler) skip the
+// per-resource credential fetch. The resource list is still fetched from the
+// Manifold API on every call. It's disabled by default.
+func WithCredentialCacheTTL(ttl time.Duration) ClientOption {
+	return func(c *Client) {
+		c.credCacheTTL = ttl
+	}
 }
 
 // NewWithClient returns a new wrapper client with a Manifold client in it.
-func New(cl *manifold.Client, team *string) (*Client, error) {
+func New(cl *manifold.Client, team *string, opts ...ClientOption) (*Client, error) {
 	c := &Client{
-		cl:         cl,
-		team:       team,
-		projectIDs: map[string]*manifold.ID{},
+		cl:          cl,
+		team:        team,
+		projectIDs:  map[string]*manifold.ID{},
+		concurrency: defaultConcurrency,
+		credCache:   map[string]credCacheEntry{},
 	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
 	return c, c.ensureTeamID()
 }
 
@@ -82,6 +121,14 @@ func (c *Client) GetResourceCredentialValues(ctx context.Context, project *strin
 // ResourceCredential with a non existing key but you've provided a Default
 // value, it will be added to the list. If no default value is given, it will
 // error.
+// If a requested credential has an Alias set, the returned CredentialValue's
+// Key is rewritten to that alias instead of the key Manifold provided.
+// Per-resource credential fetches are issued concurrently, bounded by the
+// Client's concurrency (see WithConcurrency), and short-circuit on the first
+// error. Results may be served from the credential cache instead of hitting
+// the Manifold API, if one was enabled with WithCredentialCacheTTL.
+// Credentials with a Template set are rendered from the resource's raw
+// values instead of being looked up directly; see CredentialSpec.Template.
 func (c *Client) GetResourcesCredentialValues(ctx context.Context, project *string, res []*primitives.Resource) (map[string][]*primitives.CredentialValue, error) {
 	for _, r := range res {
 		if !r.Valid() {
@@ -94,56 +141,123 @@ func (c *Client) GetResourcesCredentialValues(ctx context.Context, project *stri
 		return nil, err
 	}
 
-	resourceIDs := make([]manifold.ID, len(resources))
-	resourceLabels := map[manifold.ID]string{}
-	for i, res := range resources {
-		resourceIDs[i] = res.ID
-		resourceLabels[res.ID] = res.Body.Label
+	pID, err := c.ProjectID(project)
+	if err != nil {
+		return nil, err
 	}
 
-	credList := c.cl.Credentials.List(ctx, resourceIDs)
-	defer credList.Close()
+	concurrency := c.concurrency
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
+	sem := make(chan struct{}, concurrency)
 
+	var mu sync.Mutex
 	resourceCredentials := map[string][]*primitives.CredentialValue{}
+
+	g, ctx := errgroup.WithContext(ctx)
+	for _, resource := range resources {
+		resource := resource
+		label := resource.Body.Label
+		fingerprint := credentialSpecFingerprint(res, label)
+
+		if cached, ok := c.getCachedCredentials(pID, label, fingerprint); ok {
+			mu.Lock()
+			resourceCredentials[label] = cached
+			mu.Unlock()
+			continue
+		}
+
+		g.Go(func() error {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			creds, err := c.fetchResourceCredentials(ctx, resource, res)
+			if err != nil {
+				return err
+			}
+
+			c.setCachedCredentials(pID, label, fingerprint, creds)
+
+			mu.Lock()
+			resourceCredentials[label] = creds
+			mu.Unlock()
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	if err := fillDefaultCredentials(resourceCredentials, res); err != nil {
+		return nil, err
+	}
+
+	return resourceCredentials, nil
+}
+
+// fetchResourceCredentials fetches and resolves the credentials for a single
+// Manifold resource.
+func (c *Client) fetchResourceCredentials(ctx context.Context, resource *manifold.Resource, res []*primitives.Resource) ([]*primitives.CredentialValue, error) {
+	credList := c.cl.Credentials.List(ctx, []manifold.ID{resource.ID})
+	defer credList.Close()
+
+	raw := map[string]string{}
 	for credList.Next() {
 		cred, err := credList.Current()
 		if err != nil {
 			return nil, err
 		}
 
-		resourceCreds, ok := resourceCredentials[resourceLabels[cred.Body.ResourceID]]
-		if !ok {
-			resourceCreds = []*primitives.CredentialValue{}
-		}
-
 		for k, v := range cred.Body.Values {
-			cv := &primitives.CredentialValue{
-				CredentialSpec: primitives.CredentialSpec{
-					Key: k,
-				},
-				Value: v,
-			}
+			raw[k] = v
+		}
+	}
 
-			err := setCredentialValueFields(cv, resourceLabels[cred.Body.ResourceID], res)
-			switch err {
-			case nil:
-				resourceCreds = append(resourceCreds, cv)
-			case ErrCredentialNotSpecified:
-				// when the credential is not specified, it means that it
-				// shouldn't be listed, skip from adding.
-			default:
-				return nil, err
-			}
+	resourceCreds := []*primitives.CredentialValue{}
+	for k, v := range raw {
+		cv := &primitives.CredentialValue{
+			CredentialSpec: primitives.CredentialSpec{
+				Key: k,
+			},
+			Value: v,
 		}
 
-		resourceCredentials[resourceLabels[cred.Body.ResourceID]] = resourceCreds
+		err := setCredentialValueFields(cv, resource.Body.Label, res)
+		switch err {
+		case nil:
+			resourceCreds = append(resourceCreds, cv)
+		case ErrCredentialNotSpecified:
+			// when the credential is not specified, it means that it
+			// shouldn't be listed, skip from adding.
+		default:
+			return nil, err
+		}
 	}
 
-	if err := fillDefaultCredentials(resourceCredentials, res); err != nil {
+	templated, err := applyCredentialTemplates(resource, raw, res)
+	if err != nil {
 		return nil, err
 	}
 
-	return resourceCredentials, nil
+	if len(templated) > 0 {
+		templatedKeys := make(map[string]bool, len(templated))
+		for _, cv := range templated {
+			templatedKeys[cv.Key] = true
+		}
+
+		filtered := resourceCreds[:0]
+		for _, cv := range resourceCreds {
+			if !templatedKeys[cv.Key] {
+				filtered = append(filtered, cv)
+			}
+		}
+
+		resourceCreds = append(filtered, templated...)
+	}
+
+	return resourceCreds, nil
 }
 
 func fillDefaultCredentials(rc map[string][]*primitives.CredentialValue, res []*primitives.Resource) error {
@@ -155,10 +269,15 @@ func fillDefaultCredentials(rc map[string][]*primitives.CredentialValue, res []*
 
 		rcreds := rc[r.Spec.Label]
 		for _, cred := range r.Spec.Credentials {
+			key := cred.Key
+			if cred.Alias != "" {
+				key = cred.Alias
+			}
+
 			var set bool
 
 			for _, c := range rcreds {
-				if c.Key == cred.Key {
+				if c.Key == key {
 					set = true
 					break
 				}
@@ -170,7 +289,7 @@ func fillDefaultCredentials(rc map[string][]*primitives.CredentialValue, res []*
 				} else {
 					cv := &primitives.CredentialValue{
 						CredentialSpec: primitives.CredentialSpec{
-							Key:  cred.Key,
+							Key:  key,
 							Name: cred.Name,
 						},
 						Value: cred.Default,
@@ -202,6 +321,9 @@ func setCredentialValueFields(cv *primitives.CredentialValue, label string, res
 			if cred.Key == cv.Key {
 				cv.Default = cred.Default
 				cv.Name = cred.Name
+				if cred.Alias != "" {
+					cv.Key = cred.Alias
+				}
 				return nil
 			}
 		}
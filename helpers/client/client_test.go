@@ -0,0 +1,132 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/manifoldco/kubernetes-credentials/primitives"
+)
+
+func TestFillDefaultCredentialsUsesAliasAsKey(t *testing.T) {
+	res := []*primitives.Resource{{
+		Spec: primitives.ResourceSpec{
+			Label: "db",
+			Credentials: []primitives.CredentialSpec{
+				{Key: "DATABASE_URL", Alias: "POSTGRES_DSN", Default: "postgres://localhost"},
+			},
+		},
+	}}
+
+	rc := map[string][]*primitives.CredentialValue{}
+	if err := fillDefaultCredentials(rc, res); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	creds := rc["db"]
+	if len(creds) != 1 {
+		t.Fatalf("expected one default credential, got %d", len(creds))
+	}
+	if creds[0].Key != "POSTGRES_DSN" {
+		t.Fatalf("expected default credential to use the alias as its key, got %q", creds[0].Key)
+	}
+	if creds[0].Value != "postgres://localhost" {
+		t.Fatalf("unexpected default value: %q", creds[0].Value)
+	}
+}
+
+func TestFillDefaultCredentialsSkipsAlreadyAliasedValue(t *testing.T) {
+	res := []*primitives.Resource{{
+		Spec: primitives.ResourceSpec{
+			Label: "db",
+			Credentials: []primitives.CredentialSpec{
+				{Key: "DATABASE_URL", Alias: "POSTGRES_DSN", Default: "postgres://localhost"},
+			},
+		},
+	}}
+
+	rc := map[string][]*primitives.CredentialValue{
+		"db": {{
+			CredentialSpec: primitives.CredentialSpec{Key: "POSTGRES_DSN"},
+			Value:          "postgres://real-host",
+		}},
+	}
+
+	if err := fillDefaultCredentials(rc, res); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	creds := rc["db"]
+	if len(creds) != 1 {
+		t.Fatalf("expected the existing value not to be duplicated, got %d entries", len(creds))
+	}
+	if creds[0].Value != "postgres://real-host" {
+		t.Fatalf("expected the already-resolved value to survive, got %q", creds[0].Value)
+	}
+}
+
+func TestFillDefaultCredentialsErrorsWithoutDefault(t *testing.T) {
+	res := []*primitives.Resource{{
+		Spec: primitives.ResourceSpec{
+			Label: "db",
+			Credentials: []primitives.CredentialSpec{
+				{Key: "DATABASE_URL"},
+			},
+		},
+	}}
+
+	rc := map[string][]*primitives.CredentialValue{}
+	if err := fillDefaultCredentials(rc, res); err != ErrCredentialDefaultNotSet {
+		t.Fatalf("expected ErrCredentialDefaultNotSet, got %v", err)
+	}
+}
+
+func TestSetCredentialValueFieldsRewritesKeyToAlias(t *testing.T) {
+	res := []*primitives.Resource{{
+		Spec: primitives.ResourceSpec{
+			Label: "db",
+			Credentials: []primitives.CredentialSpec{
+				{Key: "DATABASE_URL", Alias: "POSTGRES_DSN", Name: "Database URL"},
+			},
+		},
+	}}
+
+	cv := &primitives.CredentialValue{
+		CredentialSpec: primitives.CredentialSpec{Key: "DATABASE_URL"},
+		Value:          "postgres://real-host",
+	}
+
+	if err := setCredentialValueFields(cv, "db", res); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if cv.Key != "POSTGRES_DSN" {
+		t.Fatalf("expected the resolved credential's key to be rewritten to the alias, got %q", cv.Key)
+	}
+	if cv.Name != "Database URL" {
+		t.Fatalf("expected Name to still be filled in, got %q", cv.Name)
+	}
+	if cv.Value != "postgres://real-host" {
+		t.Fatalf("expected the fetched value to be preserved, got %q", cv.Value)
+	}
+}
+
+func TestSetCredentialValueFieldsLeavesKeyAloneWithoutAlias(t *testing.T) {
+	res := []*primitives.Resource{{
+		Spec: primitives.ResourceSpec{
+			Label:       "db",
+			Credentials: []primitives.CredentialSpec{{Key: "DATABASE_URL"}},
+		},
+	}}
+
+	cv := &primitives.CredentialValue{
+		CredentialSpec: primitives.CredentialSpec{Key: "DATABASE_URL"},
+		Value:          "postgres://real-host",
+	}
+
+	if err := setCredentialValueFields(cv, "db", res); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if cv.Key != "DATABASE_URL" {
+		t.Fatalf("expected the key to be left alone without an alias, got %q", cv.Key)
+	}
+}
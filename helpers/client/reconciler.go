@@ -0,0 +1,176 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/manifoldco/kubernetes-credentials/primitives"
+)
+
+// permanentSyncErrors are configuration problems with the declared resources
+// or their CredentialSpecs, rather than transient Manifold API failures:
+// retrying on a backoff will never make them succeed, so Run surfaces them
+// immediately instead of retrying forever.
+var permanentSyncErrors = []error{
+	ErrResourceInvalid,
+	ErrCredentialDefaultNotSet,
+	ErrMultipleResourcesFound,
+}
+
+// isPermanentSyncError reports whether err is one Run should give up on
+// instead of backing off and retrying.
+func isPermanentSyncError(err error) bool {
+	for _, p := range permanentSyncErrors {
+		if errors.Is(err, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// OnChangeFunc is invoked by a Reconciler whenever the resolved credentials
+// for a resource differ from the last observed values.
+type OnChangeFunc func(label string, creds []*primitives.CredentialValue)
+
+// ReconcilerOptions configures a Reconciler.
+type ReconcilerOptions struct {
+	// Interval is how often the declared resources are re-fetched. Defaults
+	// to 30 seconds.
+	Interval time.Duration
+
+	// MinBackoff and MaxBackoff bound the jittered exponential backoff
+	// applied after a failed fetch. Default to 1 second and 1 minute.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+func (o ReconcilerOptions) withDefaults() ReconcilerOptions {
+	if o.Interval == 0 {
+		o.Interval = 30 * time.Second
+	}
+	if o.MinBackoff == 0 {
+		o.MinBackoff = time.Second
+	}
+	if o.MaxBackoff == 0 {
+		o.MaxBackoff = time.Minute
+	}
+	return o
+}
+
+// Reconciler wraps a Client and periodically re-fetches a declared set of
+// resources, invoking OnChange only for the resources whose resolved
+// credentials actually changed since the last sync.
+type Reconciler struct {
+	cl       *Client
+	project  *string
+	res      []*primitives.Resource
+	opts     ReconcilerOptions
+	OnChange OnChangeFunc
+
+	force chan struct{}
+
+	mu        sync.Mutex
+	checksums map[string]string
+}
+
+// NewReconciler builds a Reconciler that resolves res (scoped to project)
+// through cl, calling onChange whenever a resource's credentials change.
+func NewReconciler(cl *Client, project *string, res []*primitives.Resource, onChange OnChangeFunc, opts ReconcilerOptions) *Reconciler {
+	return &Reconciler{
+		cl:        cl,
+		project:   project,
+		res:       res,
+		opts:      opts.withDefaults(),
+		OnChange:  onChange,
+		force:     make(chan struct{}, 1),
+		checksums: map[string]string{},
+	}
+}
+
+// Run reconciles on every Interval tick, or immediately when ForceSync is
+// called, until ctx is done. Transient errors from the underlying Client
+// back off exponentially, with jitter, up to MaxBackoff, rather than
+// retrying on the regular Interval. A permanent error — a resource or
+// CredentialSpec that's invalid and can never resolve, see
+// isPermanentSyncError — is returned immediately instead of being retried.
+func (r *Reconciler) Run(ctx context.Context) error {
+	backoff := r.opts.MinBackoff
+
+	for {
+		wait := r.opts.Interval
+		if err := r.sync(ctx); err != nil {
+			if isPermanentSyncError(err) {
+				return err
+			}
+
+			wait = jitter(backoff)
+
+			backoff *= 2
+			if backoff > r.opts.MaxBackoff {
+				backoff = r.opts.MaxBackoff
+			}
+		} else {
+			backoff = r.opts.MinBackoff
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-r.force:
+		case <-time.After(wait):
+		}
+	}
+}
+
+// ForceSync schedules an immediate reconcile, without waiting for the next
+// Interval tick. It doesn't block until that reconcile completes.
+func (r *Reconciler) ForceSync() {
+	select {
+	case r.force <- struct{}{}:
+	default:
+		// a sync is already pending
+	}
+}
+
+func (r *Reconciler) sync(ctx context.Context) error {
+	resourceCredentials, err := r.cl.GetResourcesCredentialValues(ctx, r.project, r.res)
+	if err != nil {
+		return err
+	}
+
+	r.observe(resourceCredentials)
+	return nil
+}
+
+// observe records the checksum of each resource's resolved credentials and
+// invokes OnChange for every resource whose checksum differs from the last
+// one observed.
+func (r *Reconciler) observe(resourceCredentials map[string][]*primitives.CredentialValue) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for label, creds := range resourceCredentials {
+		checksum := checksumCredentialValues(creds)
+		if r.checksums[label] == checksum {
+			continue
+		}
+
+		r.checksums[label] = checksum
+		if r.OnChange != nil {
+			r.OnChange(label, creds)
+		}
+	}
+}
+
+// jitter returns a random duration in [d/2, d], so that multiple Reconcilers
+// backing off at the same time don't all retry in lockstep.
+func jitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+
+	return d/2 + time.Duration(rand.Int63n(int64(d)/2+1))
+}
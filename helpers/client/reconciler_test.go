@@ -0,0 +1,101 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/manifoldco/kubernetes-credentials/primitives"
+)
+
+func TestIsPermanentSyncError(t *testing.T) {
+	cases := []struct {
+		name      string
+		err       error
+		permanent bool
+	}{
+		{"invalid resource", ErrResourceInvalid, true},
+		{"missing default", ErrCredentialDefaultNotSet, true},
+		{"ambiguous label", ErrMultipleResourcesFound, true},
+		{"wrapped permanent error", fmt.Errorf("resolving creds: %w", ErrCredentialDefaultNotSet), true},
+		{"resource not found", ErrResourceNotFound, false},
+		{"context deadline", context.DeadlineExceeded, false},
+		{"generic error", errors.New("connection reset"), false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := isPermanentSyncError(tc.err); got != tc.permanent {
+				t.Errorf("isPermanentSyncError(%v) = %v, want %v", tc.err, got, tc.permanent)
+			}
+		})
+	}
+}
+
+func TestReconcilerOptionsWithDefaults(t *testing.T) {
+	opts := ReconcilerOptions{}.withDefaults()
+
+	if opts.Interval == 0 {
+		t.Error("expected Interval to default to a non-zero value")
+	}
+	if opts.MinBackoff == 0 {
+		t.Error("expected MinBackoff to default to a non-zero value")
+	}
+	if opts.MaxBackoff == 0 {
+		t.Error("expected MaxBackoff to default to a non-zero value")
+	}
+
+	custom := ReconcilerOptions{Interval: time.Minute}.withDefaults()
+	if custom.Interval != time.Minute {
+		t.Errorf("expected an explicit Interval to be preserved, got %v", custom.Interval)
+	}
+}
+
+func TestJitterBounds(t *testing.T) {
+	d := 10 * time.Second
+
+	for i := 0; i < 100; i++ {
+		j := jitter(d)
+		if j < d/2 || j > d {
+			t.Fatalf("jitter(%v) = %v, want a value in [%v, %v]", d, j, d/2, d)
+		}
+	}
+
+	if jitter(0) != 0 {
+		t.Errorf("expected jitter(0) to be 0, got %v", jitter(0))
+	}
+}
+
+func TestReconcilerOnChangeFiresOnlyOnDiff(t *testing.T) {
+	var seen []string
+	r := &Reconciler{
+		checksums: map[string]string{},
+		OnChange: func(label string, creds []*primitives.CredentialValue) {
+			seen = append(seen, label)
+		},
+	}
+
+	apply := r.observe
+
+	creds := []*primitives.CredentialValue{
+		{CredentialSpec: primitives.CredentialSpec{Key: "HOST"}, Value: "db"},
+	}
+
+	apply(map[string][]*primitives.CredentialValue{"db": creds})
+	apply(map[string][]*primitives.CredentialValue{"db": creds})
+
+	if len(seen) != 1 {
+		t.Fatalf("expected OnChange to fire once for unchanged credentials, fired %d times", len(seen))
+	}
+
+	changed := []*primitives.CredentialValue{
+		{CredentialSpec: primitives.CredentialSpec{Key: "HOST"}, Value: "other-db"},
+	}
+	apply(map[string][]*primitives.CredentialValue{"db": changed})
+
+	if len(seen) != 2 {
+		t.Fatalf("expected OnChange to fire again after a credential changed, fired %d times", len(seen))
+	}
+}
@@ -0,0 +1,111 @@
+package client
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"text/template"
+
+	manifold "github.com/manifoldco/go-manifold"
+	"github.com/manifoldco/kubernetes-credentials/primitives"
+)
+
+// missingKeyRE extracts the offending key name out of the error
+// text/template produces for a map field reference with no entry, when
+// executed with the "missingkey=error" option.
+var missingKeyRE = regexp.MustCompile(`map has no entry for key "([^"]+)"`)
+
+// applyCredentialTemplates renders every Templated CredentialSpec declared
+// for resource, against its raw (un-aliased) credential values, and returns
+// the resulting CredentialValues. Credentials without a Template are left
+// for the caller to resolve the normal way.
+func applyCredentialTemplates(resource *manifold.Resource, raw map[string]string, res []*primitives.Resource) ([]*primitives.CredentialValue, error) {
+	var templated []*primitives.CredentialValue
+
+	for _, r := range res {
+		if r.Spec.Label != resource.Body.Label {
+			continue
+		}
+
+		for _, cred := range r.Spec.Credentials {
+			if cred.Template == "" {
+				continue
+			}
+
+			value, err := renderCredentialTemplate(cred, raw)
+			if err != nil {
+				if cred.Default != "" && errors.Is(err, ErrCredentialDefaultNotSet) {
+					value = cred.Default
+				} else {
+					return nil, err
+				}
+			}
+
+			key := cred.Key
+			if cred.Alias != "" {
+				key = cred.Alias
+			}
+
+			templated = append(templated, &primitives.CredentialValue{
+				CredentialSpec: primitives.CredentialSpec{
+					Key:  key,
+					Name: cred.Name,
+				},
+				Value: value,
+			})
+		}
+	}
+
+	return templated, nil
+}
+
+// renderCredentialTemplate evaluates cred.Template against raw, the
+// resource's raw credential values, using a fixed set of helper functions:
+// env (look up a raw value, erroring if it's not set), default (fall back
+// when a value is empty), urlquote and base64. A direct field reference
+// (e.g. "{{ .PASSWORD }}") to a key that isn't in raw is treated the same
+// way as env: it returns ErrCredentialDefaultNotSet naming the missing key,
+// rather than silently rendering "<no value>".
+func renderCredentialTemplate(cred primitives.CredentialSpec, raw map[string]string) (string, error) {
+	tmpl, err := template.New(cred.Key).Option("missingkey=error").Funcs(credentialTemplateFuncs(raw)).Parse(cred.Template)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, raw); err != nil {
+		if m := missingKeyRE.FindStringSubmatch(err.Error()); m != nil {
+			return "", fmt.Errorf("%w: %s", ErrCredentialDefaultNotSet, m[1])
+		}
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// credentialTemplateFuncs builds the function map available to credential
+// templates, closed over a single resource's raw credential values.
+func credentialTemplateFuncs(raw map[string]string) template.FuncMap {
+	return template.FuncMap{
+		"env": func(key string) (string, error) {
+			v, ok := raw[key]
+			if !ok {
+				return "", fmt.Errorf("%w: %s", ErrCredentialDefaultNotSet, key)
+			}
+			return v, nil
+		},
+		"default": func(def, val string) string {
+			if val == "" {
+				return def
+			}
+			return val
+		},
+		"urlquote": url.QueryEscape,
+		"base64": func(s string) string {
+			return base64.StdEncoding.EncodeToString([]byte(s))
+		},
+	}
+}
@@ -0,0 +1,79 @@
+package client
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/manifoldco/kubernetes-credentials/primitives"
+)
+
+func TestRenderCredentialTemplate(t *testing.T) {
+	raw := map[string]string{
+		"USERNAME": "bob",
+		"PASSWORD": "hunter2",
+		"HOST":     "db",
+		"PORT":     "5432",
+		"DATABASE": "app",
+	}
+
+	cred := primitives.CredentialSpec{
+		Key:      "DATABASE_URL",
+		Template: "postgres://{{ .USERNAME }}:{{ .PASSWORD }}@{{ .HOST }}:{{ .PORT }}/{{ .DATABASE }}",
+	}
+
+	value, err := renderCredentialTemplate(cred, raw)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "postgres://bob:hunter2@db:5432/app"
+	if value != want {
+		t.Fatalf("got %q, want %q", value, want)
+	}
+}
+
+func TestRenderCredentialTemplateMissingKey(t *testing.T) {
+	raw := map[string]string{
+		"USERNAME": "bob",
+		"HOST":     "db",
+		"PORT":     "5432",
+		"DATABASE": "app",
+	}
+
+	cred := primitives.CredentialSpec{
+		Key:      "DATABASE_URL",
+		Template: "postgres://{{ .USERNAME }}:{{ .PASSWORD }}@{{ .HOST }}:{{ .PORT }}/{{ .DATABASE }}",
+	}
+
+	_, err := renderCredentialTemplate(cred, raw)
+	if !errors.Is(err, ErrCredentialDefaultNotSet) {
+		t.Fatalf("expected ErrCredentialDefaultNotSet, got %v", err)
+	}
+
+	if !containsKey(err.Error(), "PASSWORD") {
+		t.Fatalf("expected error to name the missing key, got %v", err)
+	}
+}
+
+func TestRenderCredentialTemplateEnvMissingKey(t *testing.T) {
+	raw := map[string]string{"HOST": "db"}
+
+	cred := primitives.CredentialSpec{
+		Key:      "HOST_URL",
+		Template: `{{ env "PORT" }}`,
+	}
+
+	_, err := renderCredentialTemplate(cred, raw)
+	if !errors.Is(err, ErrCredentialDefaultNotSet) {
+		t.Fatalf("expected ErrCredentialDefaultNotSet, got %v", err)
+	}
+}
+
+func containsKey(s, key string) bool {
+	for i := 0; i+len(key) <= len(s); i++ {
+		if s[i:i+len(key)] == key {
+			return true
+		}
+	}
+	return false
+}
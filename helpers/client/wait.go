@@ -0,0 +1,111 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	manifold "github.com/manifoldco/go-manifold"
+	"github.com/manifoldco/kubernetes-credentials/primitives"
+)
+
+// ErrResourceNotReady is returned by WaitForResources when its deadline
+// elapses before every requested resource reports a ready state. Callers use
+// it to tell "not ready yet" apart from ErrResourceNotFound, which means a
+// resource doesn't exist at all.
+var ErrResourceNotReady = errors.New("One or more resources are not ready yet.")
+
+// ResourceStatus is the last observed provisioning state of a resource, as
+// reported by WaitForResources.
+type ResourceStatus struct {
+	// Ready is true once the resource has finished provisioning and its
+	// credentials can be fetched.
+	Ready bool
+
+	// State is the raw state Manifold reported for the resource.
+	State string
+}
+
+// WaitOptions configures WaitForResources.
+type WaitOptions struct {
+	// MinBackoff and MaxBackoff bound the exponential backoff applied
+	// between polls. Default to 1 second and 30 seconds.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+func (o WaitOptions) withDefaults() WaitOptions {
+	if o.MinBackoff == 0 {
+		o.MinBackoff = time.Second
+	}
+	if o.MaxBackoff == 0 {
+		o.MaxBackoff = 30 * time.Second
+	}
+	return o
+}
+
+// WaitForResources polls, with exponential backoff, until every resource in
+// res reports a ready/provisioned state, or ctx's deadline elapses. Callers
+// use this to avoid materializing a Secret whose upstream resource is still
+// provisioning and whose credentials are absent or partial.
+//
+// On timeout it returns ErrResourceNotReady, along with the last observed
+// status of each resource keyed by label.
+func (c *Client) WaitForResources(ctx context.Context, project *string, res []*primitives.Resource, opts WaitOptions) (map[string]ResourceStatus, error) {
+	opts = opts.withDefaults()
+	backoff := opts.MinBackoff
+
+	for {
+		resources, err := c.GetResources(ctx, project, res)
+		if err != nil {
+			return nil, err
+		}
+
+		statuses := make(map[string]ResourceStatus, len(resources))
+		allReady := true
+		for _, resource := range resources {
+			ready := resourceReady(resource)
+			statuses[resource.Body.Label] = ResourceStatus{
+				Ready: ready,
+				State: string(resource.Body.State),
+			}
+
+			if !ready {
+				allReady = false
+			}
+		}
+
+		if allReady {
+			return statuses, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return statuses, ErrResourceNotReady
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > opts.MaxBackoff {
+			backoff = opts.MaxBackoff
+		}
+	}
+}
+
+// resourceStateAvailable is the only Resource.Body.State value Manifold
+// reports once a resource has finished provisioning and its credentials are
+// ready to fetch; every other state (e.g. "new", "provisioning", "error")
+// means the resource isn't ready yet.
+const resourceStateAvailable = "available"
+
+// resourceReady reports whether a resource's state indicates it has finished
+// provisioning and its credentials are available.
+func resourceReady(resource *manifold.Resource) bool {
+	return stateReady(string(resource.Body.State))
+}
+
+// stateReady reports whether a raw Resource.Body.State value means the
+// resource is done provisioning.
+func stateReady(state string) bool {
+	return state == resourceStateAvailable
+}
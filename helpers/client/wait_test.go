@@ -0,0 +1,33 @@
+package client
+
+import "testing"
+
+func TestStateReady(t *testing.T) {
+	cases := map[string]bool{
+		"available":    true,
+		"new":          false,
+		"provisioning": false,
+		"error":        false,
+		"":             false,
+	}
+
+	for state, want := range cases {
+		if got := stateReady(state); got != want {
+			t.Errorf("stateReady(%q) = %v, want %v", state, got, want)
+		}
+	}
+}
+
+func TestWaitOptionsWithDefaults(t *testing.T) {
+	opts := WaitOptions{}.withDefaults()
+
+	if opts.MinBackoff == 0 {
+		t.Error("expected MinBackoff to default to a non-zero value")
+	}
+	if opts.MaxBackoff == 0 {
+		t.Error("expected MaxBackoff to default to a non-zero value")
+	}
+	if opts.MinBackoff > opts.MaxBackoff {
+		t.Errorf("MinBackoff (%v) should not exceed MaxBackoff (%v)", opts.MinBackoff, opts.MaxBackoff)
+	}
+}
@@ -0,0 +1,76 @@
+// Package primitives holds the types used to describe the resources and
+// credentials a Kubernetes credential manifest is requesting, independent of
+// the underlying Manifold API models.
+package primitives
+
+import "regexp"
+
+// envVarNameRE matches legal POSIX environment variable names. Aliases are
+// validated against it since they end up as keys in a Kubernetes Secret that
+// gets mounted as environment variables.
+var envVarNameRE = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// ResourceSpec describes a Manifold resource and the credentials that should
+// be pulled from it.
+type ResourceSpec struct {
+	Label       string           `json:"label"`
+	Credentials []CredentialSpec `json:"credentials,omitempty"`
+}
+
+// Resource wraps a ResourceSpec as requested by the caller.
+type Resource struct {
+	Spec ResourceSpec `json:"spec"`
+}
+
+// Valid reports whether the resource can be resolved: it must carry a label,
+// and any requested credential alias must be a legal environment variable
+// name.
+func (r *Resource) Valid() bool {
+	if r.Spec.Label == "" {
+		return false
+	}
+
+	for _, cred := range r.Spec.Credentials {
+		if cred.Alias != "" && !envVarNameRE.MatchString(cred.Alias) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// CredentialSpec describes a single credential requested for a resource,
+// along with optional overrides for how it should be surfaced.
+type CredentialSpec struct {
+	// Key is the credential key as provided by Manifold (e.g. DATABASE_URL).
+	Key string `json:"key"`
+
+	// Name is a human readable name for the credential.
+	Name string `json:"name,omitempty"`
+
+	// Default is used to fill in the credential when Manifold doesn't have a
+	// value for it.
+	Default string `json:"default,omitempty"`
+
+	// Alias, when set, replaces Key as the key emitted on the resolved
+	// CredentialValue. This lets a manifest map a Manifold-provided key like
+	// DATABASE_URL to whatever env-var name a container image actually
+	// expects. Alias must be a legal environment variable name.
+	Alias string `json:"alias,omitempty"`
+
+	// Template, when set, is evaluated as a Go text/template against the
+	// resource's raw credential values to produce the value for this
+	// credential, instead of looking Key up directly. This lets a manifest
+	// synthesize a combined credential, e.g.
+	// "postgres://{{ .USERNAME }}:{{ .PASSWORD }}@{{ .HOST }}:{{ .PORT }}/{{ .DATABASE }}",
+	// without pushing that concern into every consumer. If the template
+	// references a raw value that isn't set, Default is used if present,
+	// the same as for a non-templated credential.
+	Template string `json:"template,omitempty"`
+}
+
+// CredentialValue is a CredentialSpec resolved to an actual value.
+type CredentialValue struct {
+	CredentialSpec
+	Value string `json:"value"`
+}
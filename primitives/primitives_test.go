@@ -0,0 +1,73 @@
+package primitives
+
+import "testing"
+
+func TestResourceValid(t *testing.T) {
+	cases := []struct {
+		name  string
+		res   Resource
+		valid bool
+	}{
+		{
+			name:  "no label",
+			res:   Resource{Spec: ResourceSpec{Label: ""}},
+			valid: false,
+		},
+		{
+			name:  "label, no credentials",
+			res:   Resource{Spec: ResourceSpec{Label: "db"}},
+			valid: true,
+		},
+		{
+			name: "unset alias",
+			res: Resource{Spec: ResourceSpec{
+				Label:       "db",
+				Credentials: []CredentialSpec{{Key: "DATABASE_URL"}},
+			}},
+			valid: true,
+		},
+		{
+			name: "legal alias",
+			res: Resource{Spec: ResourceSpec{
+				Label:       "db",
+				Credentials: []CredentialSpec{{Key: "DATABASE_URL", Alias: "POSTGRES_DSN"}},
+			}},
+			valid: true,
+		},
+		{
+			name: "alias with leading digit",
+			res: Resource{Spec: ResourceSpec{
+				Label:       "db",
+				Credentials: []CredentialSpec{{Key: "DATABASE_URL", Alias: "1_DSN"}},
+			}},
+			valid: false,
+		},
+		{
+			name: "alias with a dash",
+			res: Resource{Spec: ResourceSpec{
+				Label:       "db",
+				Credentials: []CredentialSpec{{Key: "DATABASE_URL", Alias: "POSTGRES-DSN"}},
+			}},
+			valid: false,
+		},
+		{
+			name: "empty alias on one credential, illegal alias on another",
+			res: Resource{Spec: ResourceSpec{
+				Label: "db",
+				Credentials: []CredentialSpec{
+					{Key: "USERNAME"},
+					{Key: "DATABASE_URL", Alias: "bad alias"},
+				},
+			}},
+			valid: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.res.Valid(); got != tc.valid {
+				t.Errorf("Valid() = %v, want %v", got, tc.valid)
+			}
+		})
+	}
+}